@@ -39,6 +39,51 @@
 		networkConfig.String("host", "localhost", "your computer name")
 		networkConfig.Int("port", 8080, "port to serve on")
 		networkConfig.Parse("/path/to/network.conf")
+
+	To allow deployments to override TOML values with environment variables,
+	set a prefix and call ParseEnv after Parse. A dotted name such as
+	"atlanta.enabled" is read from MYAPP_ATLANTA_ENABLED:
+
+		config.SetEnvPrefix("MYAPP")
+		config.Parse("/path/to/myconfig.conf")
+		config.ParseEnv()
+		config.ParseArgs()
+
+	Values are applied in order, so each source overrides the previous one:
+	defaults, then the TOML file, then the environment, then command-line
+	arguments.
+
+	For more control over the layering, or to load JSON or YAML files, use
+	Set.Load with an explicit list of Sources:
+
+		config.Load(
+			config.TomlFileSource("/path/to/myconfig.conf"),
+			config.EnvSource("MYAPP"),
+			config.ArgsSource(os.Args[1:]),
+		)
+
+	Long-running services can pick up edits to the TOML file without
+	restarting by calling Watch after Parse:
+
+		config.OnChange(func(name, old, new string) {
+			log.Printf("%s changed from %q to %q", name, old, new)
+		})
+		config.Watch(ctx)
+		defer config.StopWatching()
+
+	config.WriteTemplate writes an annotated TOML document of every
+	registered setting's current value, which is handy for a
+	"myapp --dump-config > config.toml" bootstrap workflow.
+
+	Instead of declaring one config variable at a time, RegisterStruct lets
+	you register a whole tree of settings from struct tags:
+
+		var cfg struct {
+			Country string `config:"country" usage:"your country" default:"Unknown"`
+		}
+		config.RegisterStruct(&cfg)
+		config.Parse("/path/to/myconfig.conf")
+		// cfg.Country now holds the loaded value
 */
 package config // import "gopkg.in/go-kornel/go-toml-config.v0"
 
@@ -151,6 +196,75 @@ func Duration(name string, value time.Duration, usage string) *time.Duration {
 	return CommandLine.Duration(name, value, usage)
 }
 
+// StringSliceVar defines a []string config with a given name and default value.
+// The argument p points to a []string variable in which to store the value of the config.
+func StringSliceVar(p *[]string, name string, value []string, usage string) {
+	CommandLine.StringSliceVar(p, name, value, usage)
+}
+
+// StringSlice defines a []string config variable with a given name and
+// default value.
+func StringSlice(name string, value []string, usage string) *[]string {
+	return CommandLine.StringSlice(name, value, usage)
+}
+
+// IntSliceVar defines a []int config with a given name and default value.
+// The argument p points to a []int variable in which to store the value of the config.
+func IntSliceVar(p *[]int, name string, value []int, usage string) {
+	CommandLine.IntSliceVar(p, name, value, usage)
+}
+
+// IntSlice defines a []int config variable with a given name and default
+// value.
+func IntSlice(name string, value []int, usage string) *[]int {
+	return CommandLine.IntSlice(name, value, usage)
+}
+
+// Float64SliceVar defines a []float64 config with a given name and default value.
+// The argument p points to a []float64 variable in which to store the value of the config.
+func Float64SliceVar(p *[]float64, name string, value []float64, usage string) {
+	CommandLine.Float64SliceVar(p, name, value, usage)
+}
+
+// Float64Slice defines a []float64 config variable with a given name and
+// default value.
+func Float64Slice(name string, value []float64, usage string) *[]float64 {
+	return CommandLine.Float64Slice(name, value, usage)
+}
+
+// DurationSliceVar defines a []time.Duration config with a given name and default value.
+// The argument p points to a []time.Duration variable in which to store the value of the config.
+func DurationSliceVar(p *[]time.Duration, name string, value []time.Duration, usage string) {
+	CommandLine.DurationSliceVar(p, name, value, usage)
+}
+
+// DurationSlice defines a []time.Duration config variable with a given name
+// and default value.
+func DurationSlice(name string, value []time.Duration, usage string) *[]time.Duration {
+	return CommandLine.DurationSlice(name, value, usage)
+}
+
+// Var defines a config variable with the given name and usage string using a
+// custom flag.Value. See Set.Var for how Appender and TypedValue are used
+// when loading values from a TOML file.
+func Var(value flag.Value, name string, usage string) {
+	CommandLine.Var(value, name, usage)
+}
+
+// SetEnvPrefix sets the prefix used by ParseEnv when deriving environment
+// variable names for the global config.Set.
+func SetEnvPrefix(prefix string) {
+	CommandLine.SetEnvPrefix(prefix)
+}
+
+// ParseEnv loads config values from environment variables into the global
+// config.Set. See Set.ParseEnv for how environment variable names are derived
+// and how this fits into the overall precedence of defaults, TOML file, env,
+// and command-line arguments.
+func ParseEnv() error {
+	return CommandLine.ParseEnv()
+}
+
 // Parse takes a path to a TOML file and loads it into the global config.Set.
 // This must be called after all config flags have been defined but before the
 // flags are accessed by the program.
@@ -1,14 +1,18 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/pelletier/go-toml"
 )
 
@@ -16,6 +20,15 @@ import (
 // has no name and has ContinueOnError error handling.
 type Set struct {
 	*flag.FlagSet
+	envPrefix  string
+	configPath string
+
+	watchMu     sync.Mutex
+	watcher     *fsnotify.Watcher
+	watchCancel context.CancelFunc
+	onChange    []OnChangeFunc
+
+	valuesMu sync.RWMutex
 }
 
 // New returns a new config.Set with the given name and error handling
@@ -23,7 +36,7 @@ type Set struct {
 // ExitOnError, and PanicOnError.
 func New(name string, errorHandling flag.ErrorHandling) *Set {
 	return &Set{
-		flag.NewFlagSet(name, errorHandling),
+		FlagSet: flag.NewFlagSet(name, errorHandling),
 	}
 }
 
@@ -118,9 +131,9 @@ func (c *Set) DurationVar(p *time.Duration, name string, value time.Duration, us
 }
 
 // Duration defines a time.Duration config variable with a given name and
-// default value.
+// default value for a config.Set.
 func (c *Set) Duration(name string, value time.Duration, usage string) *time.Duration {
-	return globalConfig.FlagSet.Duration(name, value, usage)
+	return c.FlagSet.Duration(name, value, usage)
 }
 
 // Parse takes a path to a TOML file and loads it. This must be called after
@@ -143,6 +156,8 @@ func (c *Set) Parse(path string) error {
 		return err
 	}
 
+	c.configPath = path
+
 	return nil
 }
 
@@ -164,6 +179,51 @@ func (c *Set) ParseString(str string) error {
 	return nil
 }
 
+// SetEnvPrefix sets the prefix prepended to the environment variable name
+// derived from each config setting when ParseEnv is called. For example, with
+// prefix "MYAPP" the setting "atlanta.enabled" is read from the environment
+// variable MYAPP_ATLANTA_ENABLED.
+func (c *Set) SetEnvPrefix(prefix string) {
+	c.envPrefix = prefix
+}
+
+// ParseEnv loads config values from environment variables. For every defined
+// config setting, the dotted name is upper-cased, its dots are replaced with
+// underscores, and the result is prefixed (see SetEnvPrefix) to form the
+// environment variable name. If that variable is set, its value overrides the
+// setting's current value.
+//
+// ParseEnv is meant to be called after Parse (or ParseString) and before
+// ParseArguments, so that the effective precedence is defaults, then the TOML
+// file, then the environment, then command-line arguments.
+func (c *Set) ParseEnv() error {
+	var err error
+	c.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		envValue, ok := os.LookupEnv(c.envName(f.Name))
+		if !ok {
+			return
+		}
+		if setErr := c.Set(f.Name, envValue); setErr != nil {
+			err = buildLoadError(f.Name, setErr)
+		}
+	})
+	return err
+}
+
+// envName translates a dotted config name into the environment variable name
+// it is read from: dots become underscores, letters are upper-cased, and the
+// result is prefixed with the Set's envPrefix, if any.
+func (c *Set) envName(name string) string {
+	envName := strings.ToUpper(strings.Replace(name, ".", "_", -1))
+	if c.envPrefix == "" {
+		return envName
+	}
+	return strings.ToUpper(c.envPrefix) + "_" + envName
+}
+
 // ParseArguments parses flag definitions from the argument list, which should
 // not include the command name. Must be called after all the config flags in
 // the config.Set have been defined but before the flags are accessed by the
@@ -173,6 +233,16 @@ func (c *Set) ParseArguments(arguments []string) error {
 	return c.FlagSet.Parse(arguments)
 }
 
+// PrintCurrentValues prints lines in format
+//    flagName=flagCurrentValue
+// to the os.Stderr. Useful for showing current configuration to the user.
+// The output format is subject to change.
+func (c *Set) PrintCurrentValues() {
+	c.VisitAll(func(f *flag.Flag) {
+		fmt.Fprintf(os.Stderr, "%s=%s\n", f.Name, f.Value.String())
+	})
+}
+
 // loadTomlTree recursively loads a TomlTree into this config.Set's config
 // variables.
 func (c *Set) loadTomlTree(tree *toml.TomlTree, path []string) error {
@@ -184,17 +254,76 @@ func (c *Set) loadTomlTree(tree *toml.TomlTree, path []string) error {
 			if err != nil {
 				return err
 			}
-		} else {
-			fullPath := strings.Join(append(path, key), ".")
-			err := c.Set(fullPath, fmt.Sprintf("%v", value))
-			if err != nil {
-				return buildLoadError(fullPath, err)
+			continue
+		}
+
+		name := strings.Join(append(path, key), ".")
+
+		if array, isArray := value.([]interface{}); isArray {
+			if err := c.loadTomlArray(name, array); err != nil {
+				return err
 			}
+			continue
+		}
+
+		formatted, err := c.formatTomlValue(name, value)
+		if err != nil {
+			return buildLoadError(name, err)
+		}
+		if err := c.Set(name, formatted); err != nil {
+			return buildLoadError(name, err)
 		}
 	}
 	return nil
 }
 
+// loadTomlArray loads a TOML array value into the config setting registered
+// under name. If that setting was registered with Var and its flag.Value
+// implements Appender, each element is applied with its own Append call, so
+// repeatable flags accumulate one value per array element. Otherwise the
+// elements are joined with commas and applied with a single Set call, which
+// is how the built-in slice types (StringSlice, IntSlice, Float64Slice,
+// DurationSlice) expect to receive their value.
+func (c *Set) loadTomlArray(name string, values []interface{}) error {
+	f := c.Lookup(name)
+	if f == nil {
+		return buildLoadError(name, fmt.Errorf("no such flag -%s", name))
+	}
+
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = fmt.Sprintf("%v", value)
+	}
+
+	if appender, ok := f.Value.(Appender); ok {
+		for _, s := range strs {
+			if err := appender.Append(s); err != nil {
+				return buildLoadError(name, err)
+			}
+		}
+		return nil
+	}
+
+	if err := c.Set(name, strings.Join(strs, ",")); err != nil {
+		return buildLoadError(name, err)
+	}
+	return nil
+}
+
+// formatTomlValue converts a scalar value decoded from a TOML document into
+// the string form passed to Set for the config setting registered under
+// name. If that setting's flag.Value implements TypedValue, its
+// FormatTomlValue method controls the formatting; otherwise the value is
+// formatted with %v, as loadTomlTree always has.
+func (c *Set) formatTomlValue(name string, value interface{}) (string, error) {
+	if f := c.Lookup(name); f != nil {
+		if typed, ok := f.Value.(TypedValue); ok {
+			return typed.FormatTomlValue(value)
+		}
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
 // buildLoadError takes an error from flag.FlagSet#Set and makes it a bit more
 // readable, if it recognizes the format.
 func buildLoadError(path string, err error) error {
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"testing"
+)
+
+func TestSetEnvName(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		key    string
+		want   string
+	}{
+		{"no prefix", "", "atlanta.enabled", "ATLANTA_ENABLED"},
+		{"prefix", "myapp", "atlanta.enabled", "MYAPP_ATLANTA_ENABLED"},
+		{"lower-case prefix is upper-cased", "myapp", "country", "MYAPP_COUNTRY"},
+		{"mixed-case prefix is upper-cased", "MyApp", "country", "MYAPP_COUNTRY"},
+		{"single segment name", "", "country", "COUNTRY"},
+		{"deeply nested name", "myapp", "a.b.c", "MYAPP_A_B_C"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("test", ContinueOnError)
+			c.SetEnvPrefix(tt.prefix)
+			if got := c.envName(tt.key); got != tt.want {
+				t.Errorf("envName(%q) with prefix %q = %q, want %q", tt.key, tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseEnvPrecedence checks that values layer as documented: defaults,
+// then the TOML file, then the environment, then command-line arguments,
+// each overriding the one before it.
+func TestParseEnvPrecedence(t *testing.T) {
+	const envVar = "TESTAPP_COUNTRY"
+	os.Unsetenv(envVar)
+	defer os.Unsetenv(envVar)
+
+	c := New("test", ContinueOnError)
+	country := c.String("country", "default-value", "your country")
+
+	if *country != "default-value" {
+		t.Fatalf("before loading anything, country = %q, want default-value", *country)
+	}
+
+	if err := c.ParseString(`country = "toml-value"`); err != nil {
+		t.Fatalf("ParseString: %v", err)
+	}
+	if *country != "toml-value" {
+		t.Fatalf("after ParseString, country = %q, want toml-value", *country)
+	}
+
+	os.Setenv(envVar, "env-value")
+	c.SetEnvPrefix("testapp")
+	if err := c.ParseEnv(); err != nil {
+		t.Fatalf("ParseEnv: %v", err)
+	}
+	if *country != "env-value" {
+		t.Fatalf("after ParseEnv, country = %q, want env-value", *country)
+	}
+
+	if err := c.ParseArguments([]string{"-country=args-value"}); err != nil {
+		t.Fatalf("ParseArguments: %v", err)
+	}
+	if *country != "args-value" {
+		t.Fatalf("after ParseArguments, country = %q, want args-value", *country)
+	}
+}
+
+// TestParseEnvOnlyAppliesSetVars checks that ParseEnv leaves a setting alone
+// when its environment variable isn't set, so defaults and TOML values
+// aren't clobbered by an empty environment.
+func TestParseEnvOnlyAppliesSetVars(t *testing.T) {
+	os.Unsetenv("TESTAPP_COUNTRY")
+
+	c := New("test", ContinueOnError)
+	country := c.String("country", "default-value", "your country")
+	c.SetEnvPrefix("testapp")
+
+	if err := c.ParseEnv(); err != nil {
+		t.Fatalf("ParseEnv: %v", err)
+	}
+	if *country != "default-value" {
+		t.Errorf("country = %q, want default-value (unset env var should not override)", *country)
+	}
+}
+
+func TestParseStringTableKeyViaFlagVisit(t *testing.T) {
+	c := New("test", ContinueOnError)
+	c.Bool("atlanta.enabled", false, "?")
+
+	if err := c.ParseString("[atlanta]\nenabled = true\n"); err != nil {
+		t.Fatalf("ParseString: %v", err)
+	}
+
+	var got string
+	c.VisitAll(func(f *flag.Flag) {
+		if f.Name == "atlanta.enabled" {
+			got = f.Value.String()
+		}
+	})
+	if got != "true" {
+		t.Errorf("atlanta.enabled = %q, want true", got)
+	}
+}
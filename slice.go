@@ -0,0 +1,233 @@
+package config
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Appender is implemented by flag.Value types registered with Var whose
+// values should accumulate one TOML array element at a time, rather than
+// being collapsed into a single comma-separated string. Repeatable
+// command-line flags, where passing the same flag more than once accumulates
+// values instead of overwriting them, are typically implemented this way.
+// loadTomlTree detects the Appender implementation automatically and calls
+// Append once per array element.
+type Appender interface {
+	flag.Value
+	// Append adds value to the underlying collection, leaving any values
+	// already stored in place.
+	Append(value string) error
+}
+
+// TypedValue is implemented by flag.Value types registered with Var that need
+// to control how a raw, decoded scalar TOML value is turned into the string
+// passed to Set. Most config values don't need this; loadTomlTree falls back
+// to formatting the value with fmt.Sprintf("%v", ...).
+type TypedValue interface {
+	flag.Value
+	// FormatTomlValue converts a raw value decoded from a TOML document
+	// (string, int64, float64, bool, or time.Time) into the string form
+	// passed to Set.
+	FormatTomlValue(raw interface{}) (string, error)
+}
+
+// stringSliceValue implements flag.Value for a []string. Set replaces the
+// slice by splitting value on commas.
+type stringSliceValue []string
+
+func newStringSliceValue(val []string, p *[]string) *stringSliceValue {
+	*p = val
+	return (*stringSliceValue)(p)
+}
+
+func (s *stringSliceValue) Set(value string) error {
+	if value == "" {
+		*s = nil
+		return nil
+	}
+	*s = strings.Split(value, ",")
+	return nil
+}
+
+func (s *stringSliceValue) String() string {
+	return strings.Join(*s, ",")
+}
+
+// StringSliceVar defines a []string config with a given name and default
+// value for a config.Set. The argument p points to a []string variable in
+// which to store the value of the config. The config value is a
+// comma-separated list, whether it comes from a TOML array, the command
+// line, or an environment variable.
+func (c *Set) StringSliceVar(p *[]string, name string, value []string, usage string) {
+	c.Var(newStringSliceValue(value, p), name, usage)
+}
+
+// StringSlice defines a []string config variable with a given name and
+// default value for a config.Set.
+func (c *Set) StringSlice(name string, value []string, usage string) *[]string {
+	p := new([]string)
+	c.StringSliceVar(p, name, value, usage)
+	return p
+}
+
+// intSliceValue implements flag.Value for a []int. Set replaces the slice by
+// splitting value on commas and parsing each element as an int.
+type intSliceValue []int
+
+func newIntSliceValue(val []int, p *[]int) *intSliceValue {
+	*p = val
+	return (*intSliceValue)(p)
+}
+
+func (s *intSliceValue) Set(value string) error {
+	if value == "" {
+		*s = nil
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	ints := make([]int, len(parts))
+	for i, part := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return err
+		}
+		ints[i] = v
+	}
+	*s = ints
+	return nil
+}
+
+func (s *intSliceValue) String() string {
+	parts := make([]string, len(*s))
+	for i, v := range *s {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// IntSliceVar defines a []int config with a given name and default value for
+// a config.Set. The argument p points to a []int variable in which to store
+// the value of the config.
+func (c *Set) IntSliceVar(p *[]int, name string, value []int, usage string) {
+	c.Var(newIntSliceValue(value, p), name, usage)
+}
+
+// IntSlice defines a []int config variable with a given name and default
+// value for a config.Set.
+func (c *Set) IntSlice(name string, value []int, usage string) *[]int {
+	p := new([]int)
+	c.IntSliceVar(p, name, value, usage)
+	return p
+}
+
+// float64SliceValue implements flag.Value for a []float64. Set replaces the
+// slice by splitting value on commas and parsing each element as a float64.
+type float64SliceValue []float64
+
+func newFloat64SliceValue(val []float64, p *[]float64) *float64SliceValue {
+	*p = val
+	return (*float64SliceValue)(p)
+}
+
+func (s *float64SliceValue) Set(value string) error {
+	if value == "" {
+		*s = nil
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	floats := make([]float64, len(parts))
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return err
+		}
+		floats[i] = v
+	}
+	*s = floats
+	return nil
+}
+
+func (s *float64SliceValue) String() string {
+	parts := make([]string, len(*s))
+	for i, v := range *s {
+		parts[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Float64SliceVar defines a []float64 config with a given name and default
+// value for a config.Set. The argument p points to a []float64 variable in
+// which to store the value of the config.
+func (c *Set) Float64SliceVar(p *[]float64, name string, value []float64, usage string) {
+	c.Var(newFloat64SliceValue(value, p), name, usage)
+}
+
+// Float64Slice defines a []float64 config variable with a given name and
+// default value for a config.Set.
+func (c *Set) Float64Slice(name string, value []float64, usage string) *[]float64 {
+	p := new([]float64)
+	c.Float64SliceVar(p, name, value, usage)
+	return p
+}
+
+// durationSliceValue implements flag.Value for a []time.Duration. Set
+// replaces the slice by splitting value on commas and parsing each element as
+// a time.Duration.
+type durationSliceValue []time.Duration
+
+func newDurationSliceValue(val []time.Duration, p *[]time.Duration) *durationSliceValue {
+	*p = val
+	return (*durationSliceValue)(p)
+}
+
+func (s *durationSliceValue) Set(value string) error {
+	if value == "" {
+		*s = nil
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	durations := make([]time.Duration, len(parts))
+	for i, part := range parts {
+		v, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return err
+		}
+		durations[i] = v
+	}
+	*s = durations
+	return nil
+}
+
+func (s *durationSliceValue) String() string {
+	parts := make([]string, len(*s))
+	for i, v := range *s {
+		parts[i] = v.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// DurationSliceVar defines a []time.Duration config with a given name and
+// default value for a config.Set. The argument p points to a []time.Duration
+// variable in which to store the value of the config.
+func (c *Set) DurationSliceVar(p *[]time.Duration, name string, value []time.Duration, usage string) {
+	c.Var(newDurationSliceValue(value, p), name, usage)
+}
+
+// DurationSlice defines a []time.Duration config variable with a given name
+// and default value for a config.Set.
+func (c *Set) DurationSlice(name string, value []time.Duration, usage string) *[]time.Duration {
+	p := new([]time.Duration)
+	c.DurationSliceVar(p, name, value, usage)
+	return p
+}
+
+// Var defines a config variable with the given name and usage string using a
+// custom flag.Value. For TOML arrays, if value implements Appender, each
+// array element is applied with its own Append call; if value implements
+// TypedValue, its FormatTomlValue method controls how scalar TOML values are
+// turned into the string passed to Set.
+func (c *Set) Var(value flag.Value, name string, usage string) {
+	c.FlagSet.Var(value, name, usage)
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadTomlArrayIntoSliceTypes(t *testing.T) {
+	c := New("test", ContinueOnError)
+	hosts := c.StringSlice("hosts", nil, "hosts")
+	ports := c.IntSlice("ports", nil, "ports")
+	weights := c.Float64Slice("weights", nil, "weights")
+	timeouts := c.DurationSlice("timeouts", nil, "timeouts")
+
+	doc := `
+hosts = ["a", "b", "c"]
+ports = [80, 443]
+weights = [0.5, 1.5]
+timeouts = ["1s", "2s"]
+`
+	if err := c.ParseString(doc); err != nil {
+		t.Fatalf("ParseString: %v", err)
+	}
+
+	if got, want := *hosts, []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hosts = %v, want %v", got, want)
+	}
+	if got, want := *ports, []int{80, 443}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ports = %v, want %v", got, want)
+	}
+	if got, want := *weights, []float64{0.5, 1.5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("weights = %v, want %v", got, want)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second}
+	if got := *timeouts; !reflect.DeepEqual(got, want) {
+		t.Errorf("timeouts = %v, want %v", got, want)
+	}
+}
+
+// appenderValue is a minimal flag.Value + Appender implementation used to
+// verify loadTomlTree calls Append once per array element instead of
+// collapsing the array into a single comma-separated Set call.
+type appenderValue struct {
+	values []string
+}
+
+func (a *appenderValue) String() string { return "" }
+func (a *appenderValue) Set(s string) error {
+	a.values = append(a.values, s)
+	return nil
+}
+func (a *appenderValue) Append(s string) error {
+	return a.Set(s)
+}
+
+func TestLoadTomlArrayIntoAppender(t *testing.T) {
+	c := New("test", ContinueOnError)
+	a := &appenderValue{}
+	c.Var(a, "tags", "repeatable tags")
+
+	if err := c.ParseString(`tags = ["x", "y", "z"]`); err != nil {
+		t.Fatalf("ParseString: %v", err)
+	}
+
+	want := []string{"x", "y", "z"}
+	if !reflect.DeepEqual(a.values, want) {
+		t.Errorf("tags = %v, want %v", a.values, want)
+	}
+}
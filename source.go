@@ -0,0 +1,197 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// A Source loads config values into a Set. Built-in sources cover TOML
+// files, TOML strings, JSON files, YAML files, environment variables, and
+// command-line arguments; applications can implement Source themselves to
+// layer in other providers, such as Consul, etcd, or Vault.
+type Source interface {
+	// Load applies this source's values to set.
+	Load(set *Set) error
+}
+
+// SourceFunc adapts a plain function to a Source.
+type SourceFunc func(set *Set) error
+
+// Load calls f(set).
+func (f SourceFunc) Load(set *Set) error {
+	return f(set)
+}
+
+// Load applies each source to c in order, so that later sources override
+// values applied by earlier ones. This is the recommended way to compose
+// multiple config layers, for example:
+//
+//	c.Load(
+//		config.TomlFileSource("/etc/myapp/config.toml"),
+//		config.EnvSource("MYAPP"),
+//		config.ArgsSource(os.Args[1:]),
+//	)
+//
+// which applies defaults, then the TOML file, then the environment, then
+// command-line arguments, in that order.
+func (c *Set) Load(sources ...Source) error {
+	for _, source := range sources {
+		if err := source.Load(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load applies each source to the global config.Set in order. See Set.Load.
+func Load(sources ...Source) error {
+	return CommandLine.Load(sources...)
+}
+
+// TomlFileSource returns a Source that loads config values from the TOML
+// file at path, as Set.Parse does.
+func TomlFileSource(path string) Source {
+	return SourceFunc(func(set *Set) error {
+		return set.Parse(path)
+	})
+}
+
+// TomlStringSource returns a Source that loads config values from a string
+// representation of a TOML file, as Set.ParseString does.
+func TomlStringSource(str string) Source {
+	return SourceFunc(func(set *Set) error {
+		return set.ParseString(str)
+	})
+}
+
+// EnvSource returns a Source that loads config values from environment
+// variables with the given prefix, as Set.SetEnvPrefix and Set.ParseEnv do.
+func EnvSource(prefix string) Source {
+	return SourceFunc(func(set *Set) error {
+		set.SetEnvPrefix(prefix)
+		return set.ParseEnv()
+	})
+}
+
+// ArgsSource returns a Source that parses command-line arguments, as
+// Set.ParseArguments does.
+func ArgsSource(arguments []string) Source {
+	return SourceFunc(func(set *Set) error {
+		return set.ParseArguments(arguments)
+	})
+}
+
+// JsonFileSource returns a Source that loads config values from the JSON
+// file at path. Nested JSON objects become dotted config names, mirroring
+// how TOML tables are handled by Set.Parse.
+func JsonFileSource(path string) Source {
+	return SourceFunc(func(set *Set) error {
+		jsonBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// UseNumber keeps integers as json.Number instead of decoding them
+		// as float64, which loses precision and renders large integers in
+		// scientific notation (e.g. 1234567 -> "1.234567e+06") that Int and
+		// friends can't parse back.
+		decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+		decoder.UseNumber()
+
+		var tree map[string]interface{}
+		if err := decoder.Decode(&tree); err != nil {
+			errorString := fmt.Sprintf("%s is not a valid JSON file", path)
+			return errors.New(errorString)
+		}
+
+		return set.loadMapTree(tree, []string{})
+	})
+}
+
+// YamlFileSource returns a Source that loads config values from the YAML
+// file at path. Nested YAML mappings become dotted config names, mirroring
+// how TOML tables are handled by Set.Parse.
+func YamlFileSource(path string) Source {
+	return SourceFunc(func(set *Set) error {
+		yamlBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var tree map[string]interface{}
+		if err := yaml.Unmarshal(yamlBytes, &tree); err != nil {
+			errorString := fmt.Sprintf("%s is not a valid YAML file", path)
+			return errors.New(errorString)
+		}
+
+		return set.loadMapTree(tree, []string{})
+	})
+}
+
+// loadMapTree recursively loads a generic map-based tree, as produced by
+// encoding/json or gopkg.in/yaml.v2, into this config.Set's config
+// variables. Nested maps become dotted config names and []interface{}
+// values are handled the same way loadTomlTree handles TOML arrays, so
+// JsonFileSource and YamlFileSource share the array and TypedValue support
+// used for TOML files.
+func (c *Set) loadMapTree(node interface{}, path []string) error {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if err := c.loadMapTree(child, append(path, key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[interface{}]interface{}:
+		for key, child := range value {
+			name := fmt.Sprintf("%v", key)
+			if err := c.loadMapTree(child, append(path, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		elements := make([]interface{}, len(value))
+		for i, elem := range value {
+			elements[i] = normalizeJSONNumber(elem)
+		}
+		return c.loadTomlArray(strings.Join(path, "."), elements)
+	default:
+		value = normalizeJSONNumber(value)
+		name := strings.Join(path, ".")
+		formatted, err := c.formatTomlValue(name, value)
+		if err != nil {
+			return buildLoadError(name, err)
+		}
+		if err := c.Set(name, formatted); err != nil {
+			return buildLoadError(name, err)
+		}
+		return nil
+	}
+}
+
+// normalizeJSONNumber converts a json.Number, produced by decoding a JSON
+// file with json.Decoder.UseNumber, back into an int64 or float64 so it
+// formats the same way a TOML-decoded value would. Values are tried as
+// int64 first so large whole numbers keep their exact decimal form instead
+// of round-tripping through float64's scientific notation.
+func normalizeJSONNumber(value interface{}) interface{} {
+	num, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if i, err := num.Int64(); err == nil {
+		return i
+	}
+	if f, err := num.Float64(); err == nil {
+		return f
+	}
+	return num.String()
+}
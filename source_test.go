@@ -0,0 +1,93 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadJsonFileSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-source")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	// 9223372036854775807 is math.MaxInt64, well above 2^53: if the value
+	// were ever decoded as a float64 along the way it would lose precision
+	// and Set would reject it as "invalid syntax" once formatted back out.
+	doc := `{
+		"country": "USA",
+		"big": 9223372036854775807,
+		"atlanta": {
+			"enabled": true,
+			"population": 432427
+		}
+	}`
+	if err := ioutil.WriteFile(path, []byte(doc), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c := New("test", ContinueOnError)
+	country := c.String("country", "Unknown", "your country")
+	big := c.Int64("big", 0, "a big number")
+	enabled := c.Bool("atlanta.enabled", false, "?")
+	population := c.Int("atlanta.population", 0, "population")
+
+	if err := c.Load(JsonFileSource(path)); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if *country != "USA" {
+		t.Errorf("country = %q, want USA", *country)
+	}
+	if *big != 9223372036854775807 {
+		t.Errorf("big = %d, want 9223372036854775807", *big)
+	}
+	if !*enabled {
+		t.Errorf("atlanta.enabled = false, want true")
+	}
+	if *population != 432427 {
+		t.Errorf("atlanta.population = %d, want 432427", *population)
+	}
+}
+
+func TestLoadYamlFileSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-source")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	doc := `
+country: USA
+atlanta:
+  enabled: true
+  population: 432427
+`
+	if err := ioutil.WriteFile(path, []byte(doc), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c := New("test", ContinueOnError)
+	country := c.String("country", "Unknown", "your country")
+	enabled := c.Bool("atlanta.enabled", false, "?")
+	population := c.Int("atlanta.population", 0, "population")
+
+	if err := c.Load(YamlFileSource(path)); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if *country != "USA" {
+		t.Errorf("country = %q, want USA", *country)
+	}
+	if !*enabled {
+		t.Errorf("atlanta.enabled = false, want true")
+	}
+	if *population != 432427 {
+		t.Errorf("atlanta.population = %d, want 432427", *population)
+	}
+}
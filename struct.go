@@ -0,0 +1,354 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationType is time.Duration's reflect.Type, used by parseScalarValue to
+// recognize a time.Duration pointer field and parse it with
+// time.ParseDuration instead of treating it as a plain int64.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// RegisterStruct reflects over v, which must be a non-nil pointer to a
+// struct, and registers a config setting for each leaf field tagged with a
+// `config` struct tag, for example:
+//
+//	type Config struct {
+//		Country string `config:"country" usage:"your country" default:"Unknown"`
+//		Atlanta struct {
+//			Enabled    bool `config:"enabled" usage:"?" default:"false"`
+//			Population int  `config:"population" usage:"population" default:"0"`
+//		} `config:"atlanta"`
+//	}
+//
+// Nested structs build dotted config names from their own `config` tag (or,
+// if the tag is absent, their field name, lower-cased) combined with their
+// parent's. Supported leaf types are the ones Set already has constructors
+// for — bool, int, int64, uint, uint64, string, float64, time.Duration,
+// []string, []int, []float64, and []time.Duration — plus pointers to any of
+// the scalar types in that list (pointers to slices are not supported).
+// Pointer fields are left nil unless the field has a `default` tag or the
+// setting is actually set by Parse, ParseEnv, or ParseArguments. Untagged
+// leaf fields are skipped.
+//
+// RegisterStruct must be called before the config.Set's flags are parsed;
+// after that, the struct's fields hold whatever was loaded.
+func (c *Set) RegisterStruct(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("config: RegisterStruct requires a non-nil pointer to a struct")
+	}
+	return c.registerStructFields(rv.Elem(), "")
+}
+
+// RegisterStruct registers a config setting for each tagged leaf field of v
+// on the global config.Set. See Set.RegisterStruct.
+func RegisterStruct(v interface{}) error {
+	return CommandLine.RegisterStruct(v)
+}
+
+// registerStructFields walks structVal's fields, registering a leaf setting
+// for each one tagged with `config` and recursing into nested structs,
+// building dotted names under prefix as it goes.
+func (c *Set) registerStructFields(structVal reflect.Value, prefix string) error {
+	structType := structVal.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		fieldVal := structVal.Field(i)
+		tagName, tagged := field.Tag.Lookup("config")
+
+		if fieldVal.Kind() == reflect.Struct {
+			nestedName := tagName
+			if !tagged {
+				nestedName = strings.ToLower(field.Name)
+			}
+			if err := c.registerStructFields(fieldVal, joinDotted(prefix, nestedName)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !tagged {
+			continue
+		}
+
+		name := joinDotted(prefix, tagName)
+		usage := field.Tag.Get("usage")
+		defaultTag := field.Tag.Get("default")
+		if err := c.registerLeaf(fieldVal, name, usage, defaultTag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinDotted(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// registerLeaf registers a single config setting bound directly to
+// fieldVal, an addressable struct field, dispatching to the Set constructor
+// for its concrete type.
+func (c *Set) registerLeaf(fieldVal reflect.Value, name, usage, defaultTag string) error {
+	if fieldVal.Kind() == reflect.Ptr {
+		c.Var(&reflectPointerValue{field: fieldVal}, name, usage)
+		if defaultTag != "" {
+			if err := c.Set(name, defaultTag); err != nil {
+				return buildLoadError(name, err)
+			}
+		}
+		return nil
+	}
+
+	switch addr := fieldVal.Addr().Interface().(type) {
+	case *bool:
+		def := *addr
+		if defaultTag != "" {
+			v, err := strconv.ParseBool(defaultTag)
+			if err != nil {
+				return err
+			}
+			def = v
+		}
+		c.BoolVar(addr, name, def, usage)
+	case *int:
+		def := *addr
+		if defaultTag != "" {
+			v, err := strconv.Atoi(defaultTag)
+			if err != nil {
+				return err
+			}
+			def = v
+		}
+		c.IntVar(addr, name, def, usage)
+	case *int64:
+		def := *addr
+		if defaultTag != "" {
+			v, err := strconv.ParseInt(defaultTag, 0, 64)
+			if err != nil {
+				return err
+			}
+			def = v
+		}
+		c.Int64Var(addr, name, def, usage)
+	case *uint:
+		def := *addr
+		if defaultTag != "" {
+			v, err := strconv.ParseUint(defaultTag, 0, strconv.IntSize)
+			if err != nil {
+				return err
+			}
+			def = uint(v)
+		}
+		c.UintVar(addr, name, def, usage)
+	case *uint64:
+		def := *addr
+		if defaultTag != "" {
+			v, err := strconv.ParseUint(defaultTag, 0, 64)
+			if err != nil {
+				return err
+			}
+			def = v
+		}
+		c.Uint64Var(addr, name, def, usage)
+	case *string:
+		def := *addr
+		if defaultTag != "" {
+			def = defaultTag
+		}
+		c.StringVar(addr, name, def, usage)
+	case *float64:
+		def := *addr
+		if defaultTag != "" {
+			v, err := strconv.ParseFloat(defaultTag, 64)
+			if err != nil {
+				return err
+			}
+			def = v
+		}
+		c.Float64Var(addr, name, def, usage)
+	case *time.Duration:
+		def := *addr
+		if defaultTag != "" {
+			v, err := time.ParseDuration(defaultTag)
+			if err != nil {
+				return err
+			}
+			def = v
+		}
+		c.DurationVar(addr, name, def, usage)
+	case *[]string:
+		def := *addr
+		if defaultTag != "" {
+			def = strings.Split(defaultTag, ",")
+		}
+		c.StringSliceVar(addr, name, def, usage)
+	case *[]int:
+		def := *addr
+		if defaultTag != "" {
+			parsed, err := parseIntList(defaultTag)
+			if err != nil {
+				return err
+			}
+			def = parsed
+		}
+		c.IntSliceVar(addr, name, def, usage)
+	case *[]float64:
+		def := *addr
+		if defaultTag != "" {
+			parsed, err := parseFloat64List(defaultTag)
+			if err != nil {
+				return err
+			}
+			def = parsed
+		}
+		c.Float64SliceVar(addr, name, def, usage)
+	case *[]time.Duration:
+		def := *addr
+		if defaultTag != "" {
+			parsed, err := parseDurationList(defaultTag)
+			if err != nil {
+				return err
+			}
+			def = parsed
+		}
+		c.DurationSliceVar(addr, name, def, usage)
+	default:
+		return fmt.Errorf("config: field for %s has unsupported type %s", name, fieldVal.Type())
+	}
+	return nil
+}
+
+func parseIntList(s string) ([]int, error) {
+	parts := strings.Split(s, ",")
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func parseFloat64List(s string) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func parseDurationList(s string) ([]time.Duration, error) {
+	parts := strings.Split(s, ",")
+	values := make([]time.Duration, len(parts))
+	for i, part := range parts {
+		v, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// reflectPointerValue implements flag.Value for a pointer-typed struct
+// field, such as *string or *time.Duration. The field is left nil until Set
+// is called, so callers can tell an explicitly configured zero value apart
+// from a setting that was never set.
+type reflectPointerValue struct {
+	field reflect.Value
+}
+
+func (r *reflectPointerValue) String() string {
+	if !r.field.IsValid() || r.field.IsNil() {
+		return ""
+	}
+	return fmt.Sprintf("%v", r.field.Elem().Interface())
+}
+
+func (r *reflectPointerValue) Set(s string) error {
+	elemType := r.field.Type().Elem()
+	parsed, err := parseScalarValue(elemType, s)
+	if err != nil {
+		return err
+	}
+	ptr := reflect.New(elemType)
+	ptr.Elem().Set(parsed)
+	r.field.Set(ptr)
+	return nil
+}
+
+// parseScalarValue parses s as a value of elemType, which must be one of the
+// scalar types Set can register: bool, int, int64, uint, uint64, string,
+// float64, or time.Duration.
+func parseScalarValue(elemType reflect.Type, s string) (reflect.Value, error) {
+	if elemType == durationType {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(v), nil
+	}
+
+	switch elemType.Kind() {
+	case reflect.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(v), nil
+	case reflect.Int:
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(v), nil
+	case reflect.Int64:
+		v, err := strconv.ParseInt(s, 0, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(v), nil
+	case reflect.Uint:
+		v, err := strconv.ParseUint(s, 0, strconv.IntSize)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(uint(v)), nil
+	case reflect.Uint64:
+		v, err := strconv.ParseUint(s, 0, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(v), nil
+	case reflect.String:
+		return reflect.ValueOf(s), nil
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(v), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("config: unsupported pointer field type %s", elemType)
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import "testing"
+
+func TestRegisterStruct(t *testing.T) {
+	var cfg struct {
+		Country string `config:"country" usage:"your country" default:"Unknown"`
+		Atlanta struct {
+			Enabled    bool `config:"enabled" usage:"?" default:"false"`
+			Population int  `config:"population" usage:"population" default:"0"`
+		} `config:"atlanta"`
+		Label *string `config:"label" usage:"optional label"`
+	}
+
+	c := New("test", ContinueOnError)
+	if err := c.RegisterStruct(&cfg); err != nil {
+		t.Fatalf("RegisterStruct: %v", err)
+	}
+
+	if cfg.Country != "Unknown" {
+		t.Errorf("before Parse, Country = %q, want Unknown (from default tag)", cfg.Country)
+	}
+	if cfg.Label != nil {
+		t.Errorf("before Parse, Label = %v, want nil", cfg.Label)
+	}
+
+	doc := `
+country = "USA"
+label = "prod"
+
+[atlanta]
+enabled = true
+population = 432427
+`
+	if err := c.ParseString(doc); err != nil {
+		t.Fatalf("ParseString: %v", err)
+	}
+
+	if cfg.Country != "USA" {
+		t.Errorf("Country = %q, want USA", cfg.Country)
+	}
+	if !cfg.Atlanta.Enabled {
+		t.Errorf("Atlanta.Enabled = false, want true")
+	}
+	if cfg.Atlanta.Population != 432427 {
+		t.Errorf("Atlanta.Population = %d, want 432427", cfg.Atlanta.Population)
+	}
+	if cfg.Label == nil || *cfg.Label != "prod" {
+		t.Errorf("Label = %v, want \"prod\"", cfg.Label)
+	}
+}
+
+func TestRegisterStructRejectsNonPointer(t *testing.T) {
+	c := New("test", ContinueOnError)
+	var notAPointer struct{}
+	if err := c.RegisterStruct(notAPointer); err == nil {
+		t.Fatalf("expected an error when registering a non-pointer value")
+	}
+}
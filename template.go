@@ -0,0 +1,165 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// boolFlag is implemented by flag.Value types that represent a bool, such as
+// the ones created by Bool and BoolVar. It mirrors the unexported interface
+// the flag package itself uses to recognize boolean flags.
+type boolFlag interface {
+	flag.Value
+	IsBoolFlag() bool
+}
+
+// WriteTemplate writes a TOML document to w with every config setting
+// registered on c set to its current value. Dotted names such as
+// "atlanta.enabled" are regrouped into TOML tables (e.g. [atlanta]), and each
+// setting is preceded by a comment with its usage string and its default
+// value. This gives applications a way to offer a
+// "myapp --dump-config > config.toml" workflow that documents every knob.
+func (c *Set) WriteTemplate(w io.Writer) error {
+	root := newTemplateNode()
+	c.VisitAll(func(f *flag.Flag) {
+		root.insert(strings.Split(f.Name, "."), f)
+	})
+	return root.write(w, nil)
+}
+
+// WriteTemplate writes a TOML document for the global config.Set. See
+// Set.WriteTemplate.
+func WriteTemplate(w io.Writer) error {
+	return CommandLine.WriteTemplate(w)
+}
+
+// templateNode is one level of the dotted-name tree built by WriteTemplate:
+// a TOML table whose children are either leaf settings (flag set) or nested
+// tables (children set).
+type templateNode struct {
+	flag     *flag.Flag
+	order    []string
+	children map[string]*templateNode
+}
+
+func newTemplateNode() *templateNode {
+	return &templateNode{children: make(map[string]*templateNode)}
+}
+
+func (n *templateNode) child(name string) *templateNode {
+	child, ok := n.children[name]
+	if !ok {
+		child = newTemplateNode()
+		n.children[name] = child
+		n.order = append(n.order, name)
+	}
+	return child
+}
+
+func (n *templateNode) insert(path []string, f *flag.Flag) {
+	child := n.child(path[0])
+	if len(path) == 1 {
+		child.flag = f
+		return
+	}
+	child.insert(path[1:], f)
+}
+
+// write emits this node's leaf settings, then recurses into its child tables
+// under path, which is the dotted table name this node represents (nil at
+// the root, which has no table header of its own).
+func (n *templateNode) write(w io.Writer, path []string) error {
+	for _, name := range n.order {
+		child := n.children[name]
+		if child.flag == nil {
+			continue
+		}
+		if err := writeTemplateSetting(w, name, child.flag); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range n.order {
+		child := n.children[name]
+		if child.flag != nil {
+			continue
+		}
+		tablePath := append(append([]string{}, path...), name)
+		if _, err := fmt.Fprintf(w, "[%s]\n", strings.Join(tablePath, ".")); err != nil {
+			return err
+		}
+		if err := child.write(w, tablePath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeTemplateSetting writes a single TOML key, preceded by comments
+// carrying its usage string and default value.
+func writeTemplateSetting(w io.Writer, name string, f *flag.Flag) error {
+	if f.Usage != "" {
+		if _, err := fmt.Fprintf(w, "# %s\n", f.Usage); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "# default: %s\n", f.DefValue); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s = %s\n\n", name, templateValueLiteral(f))
+	return err
+}
+
+// templateValueLiteral renders f's current value as a TOML literal: numbers
+// and booleans unquoted, everything else as a quoted TOML string. The
+// built-in slice types are rendered as TOML arrays.
+func templateValueLiteral(f *flag.Flag) string {
+	switch v := f.Value.(type) {
+	case *stringSliceValue:
+		return templateStringArray([]string(*v))
+	case *intSliceValue:
+		strs := make([]string, len(*v))
+		for i, n := range *v {
+			strs[i] = strconv.Itoa(n)
+		}
+		return templateNumberArray(strs)
+	case *float64SliceValue:
+		strs := make([]string, len(*v))
+		for i, n := range *v {
+			strs[i] = strconv.FormatFloat(n, 'g', -1, 64)
+		}
+		return templateNumberArray(strs)
+	case *durationSliceValue:
+		strs := make([]string, len(*v))
+		for i, d := range *v {
+			strs[i] = d.String()
+		}
+		return templateStringArray(strs)
+	}
+
+	value := f.Value.String()
+
+	if _, ok := f.Value.(boolFlag); ok {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+func templateStringArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func templateNumberArray(values []string) string {
+	return "[" + strings.Join(values, ", ") + "]"
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestWriteTemplateRoundTrip checks that the TOML WriteTemplate produces can
+// be parsed back into a fresh Set and yields the same values.
+func TestWriteTemplateRoundTrip(t *testing.T) {
+	c := New("test", ContinueOnError)
+	country := c.String("country", "Unknown", "your country")
+	enabled := c.Bool("atlanta.enabled", false, "?")
+	population := c.Int("atlanta.population", 0, "population")
+	hosts := c.StringSlice("hosts", nil, "hosts")
+
+	doc := `
+country = "USA"
+hosts = ["a", "b"]
+
+[atlanta]
+enabled = true
+population = 432427
+`
+	if err := c.ParseString(doc); err != nil {
+		t.Fatalf("ParseString: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteTemplate(&buf); err != nil {
+		t.Fatalf("WriteTemplate: %v", err)
+	}
+
+	c2 := New("test2", ContinueOnError)
+	country2 := c2.String("country", "Unknown", "your country")
+	enabled2 := c2.Bool("atlanta.enabled", false, "?")
+	population2 := c2.Int("atlanta.population", 0, "population")
+	hosts2 := c2.StringSlice("hosts", nil, "hosts")
+
+	if err := c2.ParseString(buf.String()); err != nil {
+		t.Fatalf("ParseString of generated template failed: %v\ntemplate:\n%s", err, buf.String())
+	}
+
+	if *country2 != *country {
+		t.Errorf("country = %q, want %q", *country2, *country)
+	}
+	if *enabled2 != *enabled {
+		t.Errorf("atlanta.enabled = %v, want %v", *enabled2, *enabled)
+	}
+	if *population2 != *population {
+		t.Errorf("atlanta.population = %v, want %v", *population2, *population)
+	}
+	if len(*hosts2) != len(*hosts) || (*hosts2)[0] != (*hosts)[0] || (*hosts2)[1] != (*hosts)[1] {
+		t.Errorf("hosts = %v, want %v", *hosts2, *hosts)
+	}
+}
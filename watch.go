@@ -0,0 +1,237 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pelletier/go-toml"
+)
+
+// debounceDelay is how long Watch waits after a file event before reloading,
+// so that a burst of writes to the config file results in a single reload.
+const debounceDelay = 100 * time.Millisecond
+
+// OnChangeFunc is called by Watch after a config value changes because of a
+// file edit. name is the dotted config name; old and new are its string
+// values before and after the change.
+type OnChangeFunc func(name string, old string, new string)
+
+// OnChange registers fn to be called for every config setting that changes
+// value when Watch reloads the config file. Multiple handlers may be
+// registered; each is called, in the order registered, once per changed
+// setting.
+func (c *Set) OnChange(fn OnChangeFunc) {
+	c.watchMu.Lock()
+	defer c.watchMu.Unlock()
+	c.onChange = append(c.onChange, fn)
+}
+
+// OnChange registers fn to be called for every config setting that changes
+// value when Watch reloads the global config.Set's config file.
+func OnChange(fn OnChangeFunc) {
+	CommandLine.OnChange(fn)
+}
+
+// Watch monitors the file previously passed to Parse and re-applies it to c
+// whenever the file is written, debouncing bursts of writes into a single
+// reload. If the new file fails to parse, c's values are rolled back to
+// what they were before the reload was attempted. Watch returns once the
+// watch has started; call StopWatching, or cancel ctx, to stop it. Calling
+// Watch again before stopping a previous watch stops that one first.
+//
+// Watch watches the config file's directory rather than the file itself, so
+// editors that save by writing a temp file and renaming it over the
+// original (vim and many IDEs do this) keep being picked up after the first
+// save, even though that replaces the file's inode.
+//
+// A reload applies its values while holding the same lock as RLock, so code
+// that reads more than one config value and needs them to reflect a single,
+// consistent reload should wrap those reads in c.RLock()/c.RUnlock(). Reads
+// made without RLock are not synchronized with a reload.
+func (c *Set) Watch(ctx context.Context) error {
+	if c.configPath == "" {
+		return errors.New("config: Watch called before Parse")
+	}
+
+	c.StopWatching()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(c.configPath)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	c.watchMu.Lock()
+	c.watcher = watcher
+	c.watchCancel = cancel
+	c.watchMu.Unlock()
+
+	go c.watchLoop(ctx, watcher)
+
+	return nil
+}
+
+// Watch monitors the file previously passed to Parse for the global
+// config.Set. See Set.Watch.
+func Watch(ctx context.Context) error {
+	return CommandLine.Watch(ctx)
+}
+
+// StopWatching stops a watch started with Watch. It is safe to call even if
+// Watch was never called, or has already stopped.
+func (c *Set) StopWatching() {
+	c.watchMu.Lock()
+	cancel := c.watchCancel
+	c.watchCancel = nil
+	c.watchMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// StopWatching stops a watch started on the global config.Set with Watch.
+func StopWatching() {
+	CommandLine.StopWatching()
+}
+
+// RLock acquires a read lock that excludes a concurrent Watch reload. Code
+// that reads more than one related config value and needs them to reflect a
+// single, consistent reload should hold this lock across those reads.
+func (c *Set) RLock() {
+	c.valuesMu.RLock()
+}
+
+// RUnlock releases a lock acquired with RLock.
+func (c *Set) RUnlock() {
+	c.valuesMu.RUnlock()
+}
+
+// watchLoop reads file events from watcher, debounces them, and reloads the
+// config file on c's behalf until ctx is canceled. watcher is watching the
+// config file's directory, so events are filtered down to ones naming the
+// config file itself.
+func (c *Set) watchLoop(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+
+	var debounce *time.Timer
+	reload := make(chan struct{}, 1)
+
+	for {
+		select {
+		case <-ctx.Done():
+			if debounce != nil {
+				debounce.Stop()
+			}
+			return
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(c.configPath) {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if debounce == nil {
+				debounce = time.AfterFunc(debounceDelay, func() {
+					select {
+					case reload <- struct{}{}:
+					default:
+					}
+				})
+			} else {
+				debounce.Reset(debounceDelay)
+			}
+
+		case <-reload:
+			c.reloadFromFile()
+
+		case _, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// reloadFromFile re-parses c's config file and applies it, rolling back to
+// the previous values if the new file doesn't parse or doesn't load
+// cleanly. The whole attempt runs under c.valuesMu so it can't interleave
+// with another reload or with reads made under RLock.
+func (c *Set) reloadFromFile() {
+	c.valuesMu.Lock()
+	defer c.valuesMu.Unlock()
+
+	before := c.snapshotValues()
+
+	configBytes, err := ioutil.ReadFile(c.configPath)
+	if err != nil {
+		return
+	}
+
+	tomlTree, err := toml.Load(string(configBytes))
+	if err != nil {
+		return
+	}
+
+	if err := c.loadTomlTree(tomlTree, []string{}); err != nil {
+		c.restoreValues(before)
+		return
+	}
+
+	c.notifyChanges(before)
+}
+
+// snapshotValues captures the current string value of every registered flag,
+// for use as a rollback point by reloadFromFile.
+func (c *Set) snapshotValues() map[string]string {
+	values := make(map[string]string)
+	c.VisitAll(func(f *flag.Flag) {
+		values[f.Name] = f.Value.String()
+	})
+	return values
+}
+
+// restoreValues re-applies a snapshot taken by snapshotValues, undoing a
+// partially applied reload.
+func (c *Set) restoreValues(values map[string]string) {
+	for name, value := range values {
+		c.Set(name, value)
+	}
+}
+
+// notifyChanges calls any registered OnChange handlers for every flag whose
+// value differs from its value in before.
+func (c *Set) notifyChanges(before map[string]string) {
+	c.watchMu.Lock()
+	handlers := append([]OnChangeFunc(nil), c.onChange...)
+	c.watchMu.Unlock()
+
+	if len(handlers) == 0 {
+		return
+	}
+
+	c.VisitAll(func(f *flag.Flag) {
+		old := before[f.Name]
+		newValue := f.Value.String()
+		if old == newValue {
+			return
+		}
+		for _, handler := range handlers {
+			handler(f.Name, old, newValue)
+		}
+	})
+}
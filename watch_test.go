@@ -0,0 +1,161 @@
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond every 5ms until it returns true or timeout elapses,
+// failing the test if it never does.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !cond() {
+		t.Fatalf("condition not met within %s", timeout)
+	}
+}
+
+func TestWatchReloadsOnChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-watch")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.toml")
+	if err := ioutil.WriteFile(path, []byte(`country = "USA"`), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c := New("test", ContinueOnError)
+	country := c.String("country", "Unknown", "your country")
+	if err := c.Parse(path); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Watch(ctx); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer c.StopWatching()
+
+	if err := ioutil.WriteFile(path, []byte(`country = "Canada"`), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	waitFor(t, 2*time.Second, func() bool {
+		c.RLock()
+		defer c.RUnlock()
+		return *country == "Canada"
+	})
+}
+
+func TestWatchRollsBackOnInvalidToml(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-watch")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.toml")
+	if err := ioutil.WriteFile(path, []byte(`country = "USA"`), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c := New("test", ContinueOnError)
+	country := c.String("country", "Unknown", "your country")
+	if err := c.Parse(path); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var changed bool
+	c.OnChange(func(name, old, new string) {
+		changed = true
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Watch(ctx); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer c.StopWatching()
+
+	if err := ioutil.WriteFile(path, []byte(`this is not valid toml `), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	// Give the watcher time to pick up the bad write and attempt (and abort)
+	// a reload, then confirm it rolled back instead of leaving a partial or
+	// zero value, and never fired OnChange.
+	time.Sleep(500 * time.Millisecond)
+
+	c.RLock()
+	defer c.RUnlock()
+	if *country != "USA" {
+		t.Errorf("country = %q after invalid reload, want USA (rolled back)", *country)
+	}
+	if changed {
+		t.Errorf("OnChange fired for a reload that should have rolled back")
+	}
+}
+
+func TestWatchDebounceCoalescesBurst(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-watch")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.toml")
+	if err := ioutil.WriteFile(path, []byte(`country = "USA"`), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	c := New("test", ContinueOnError)
+	country := c.String("country", "Unknown", "your country")
+	if err := c.Parse(path); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var reloads int
+	c.OnChange(func(name, old, new string) {
+		reloads++
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Watch(ctx); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer c.StopWatching()
+
+	for i, value := range []string{"Canada", "Mexico", "Brazil"} {
+		if err := ioutil.WriteFile(path, []byte(`country = "`+value+`"`), 0644); err != nil {
+			t.Fatalf("WriteFile %d: %v", i, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	waitFor(t, 2*time.Second, func() bool {
+		c.RLock()
+		defer c.RUnlock()
+		return *country == "Brazil"
+	})
+
+	// The burst happened within a single debounce window, so OnChange should
+	// have fired once, not once per write.
+	if reloads != 1 {
+		t.Errorf("OnChange fired %d times for a debounced burst, want 1", reloads)
+	}
+}